@@ -0,0 +1,26 @@
+package main
+
+import (
+	"math/rand"
+	"net/http"
+)
+
+// badStatuses is the pool of non-2xx codes /bad picks from, so operators can
+// exercise alerting against the status-labeled HTTP metrics without waiting
+// for real errors.
+var badStatuses = []int{
+	http.StatusBadRequest,
+	http.StatusUnauthorized,
+	http.StatusForbidden,
+	http.StatusNotFound,
+	http.StatusTooManyRequests,
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+}
+
+// badHandler randomly returns one of badStatuses.
+func badHandler(w http.ResponseWriter, r *http.Request) {
+	status := badStatuses[rand.Intn(len(badStatuses))]
+	http.Error(w, http.StatusText(status), status)
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// defaultLatencyBuckets cover typical API latency SLOs (5ms-10s). They are
+// the same bounds as client_golang's prometheus.DefBuckets, listed
+// explicitly here as the default for -latency-buckets.
+var defaultLatencyBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// parseBuckets parses a comma-separated list of histogram bucket bounds,
+// rejecting anything that isn't strictly increasing.
+func parseBuckets(s string) ([]float64, error) {
+	parts := strings.Split(s, ",")
+	buckets := make([]float64, 0, len(parts))
+	for _, p := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(p), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bucket %q: %w", p, err)
+		}
+		if len(buckets) > 0 && v <= buckets[len(buckets)-1] {
+			return nil, fmt.Errorf("buckets must be strictly increasing, got %v after %v", v, buckets[len(buckets)-1])
+		}
+		buckets = append(buckets, v)
+	}
+	return buckets, nil
+}
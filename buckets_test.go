@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestParseBuckets(t *testing.T) {
+	got, err := parseBuckets("0.1, 0.5,1,2.5")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []float64{0.1, 0.5, 1, 2.5}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestParseBucketsRejectsNonMonotonic(t *testing.T) {
+	if _, err := parseBuckets("1,0.5,2"); err == nil {
+		t.Fatal("expected error for non-monotonic bucket list")
+	}
+}
+
+func TestParseBucketsRejectsEqualValues(t *testing.T) {
+	if _, err := parseBuckets("1,1,2"); err == nil {
+		t.Fatal("expected error for duplicate bucket bounds")
+	}
+}
+
+func TestParseBucketsRejectsGarbage(t *testing.T) {
+	if _, err := parseBuckets("1,not-a-number,2"); err == nil {
+		t.Fatal("expected error for non-numeric bucket")
+	}
+}
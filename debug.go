@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const redactedValue = "REDACTED"
+
+// secretKeyPatterns match env var names commonly used for credentials.
+// Matching is a case-insensitive substring check against the key.
+var secretKeyPatterns = []string{
+	"KEY",
+	"SECRET",
+	"TOKEN",
+	"PASSWORD",
+	"PASSWD",
+	"CREDENTIAL",
+	"AUTH",
+}
+
+func isSecretKey(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, pattern := range secretKeyPatterns {
+		if strings.Contains(upper, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// envHandler dumps the process environment as JSON, redacting values whose
+// key looks like it holds a credential. It must only ever be registered
+// when -debug is set: it's a diagnostic aid for local/staging use, not
+// something to expose in production.
+func envHandler(w http.ResponseWriter, r *http.Request) {
+	env := make(map[string]string)
+	for _, kv := range os.Environ() {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		key, value := parts[0], parts[1]
+		if isSecretKey(key) {
+			value = redactedValue
+		}
+		env[key] = value
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(env)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnvHandlerRedactsSecrets(t *testing.T) {
+	t.Setenv("ORKA_TEST_API_KEY", "super-secret-value")
+	t.Setenv("ORKA_TEST_DB_PASSWORD", "hunter2")
+	t.Setenv("ORKA_TEST_PLAIN_VAR", "not-a-secret")
+
+	rec := httptest.NewRecorder()
+	envHandler(rec, httptest.NewRequest(http.MethodGet, "/env", nil))
+
+	var env map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	if env["ORKA_TEST_API_KEY"] != redactedValue {
+		t.Fatalf("expected API key to be redacted, got %q", env["ORKA_TEST_API_KEY"])
+	}
+	if env["ORKA_TEST_DB_PASSWORD"] != redactedValue {
+		t.Fatalf("expected password to be redacted, got %q", env["ORKA_TEST_DB_PASSWORD"])
+	}
+	if env["ORKA_TEST_PLAIN_VAR"] != "not-a-secret" {
+		t.Fatalf("expected non-secret var to pass through unchanged, got %q", env["ORKA_TEST_PLAIN_VAR"])
+	}
+}
+
+func TestIsSecretKey(t *testing.T) {
+	cases := map[string]bool{
+		"AWS_SECRET_ACCESS_KEY": true,
+		"API_TOKEN":             true,
+		"DB_PASSWORD":           true,
+		"SOME_AUTH_HEADER":      true,
+		"PATH":                  false,
+		"HOME":                  false,
+	}
+	for name, want := range cases {
+		if got := isSecretKey(name); got != want {
+			t.Errorf("isSecretKey(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
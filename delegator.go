@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// delegator is a http.ResponseWriter that additionally exposes the status
+// code and number of bytes written, so instrumentation code can observe the
+// true outcome of a request instead of assuming success.
+type delegator interface {
+	http.ResponseWriter
+	Status() int
+	Written() int64
+}
+
+// responseWriterDelegator wraps a http.ResponseWriter and records the status
+// code and bytes written as they happen. It deliberately mirrors the
+// delegator pattern used by promhttp internally, since the additional
+// optional interfaces (CloseNotifier, Flusher, Hijacker, Pusher, ReaderFrom)
+// have to be forwarded selectively: a caller that type-asserts the
+// ResponseWriter for one of them must only succeed if the underlying writer
+// actually supports it.
+type responseWriterDelegator struct {
+	http.ResponseWriter
+
+	status      int
+	written     int64
+	wroteHeader bool
+
+	observeWriteHeader func(status int)
+}
+
+func (d *responseWriterDelegator) WriteHeader(code int) {
+	d.status = code
+	d.wroteHeader = true
+	d.ResponseWriter.WriteHeader(code)
+	if d.observeWriteHeader != nil {
+		d.observeWriteHeader(code)
+	}
+}
+
+func (d *responseWriterDelegator) Write(b []byte) (int, error) {
+	if !d.wroteHeader {
+		d.WriteHeader(http.StatusOK)
+	}
+	n, err := d.ResponseWriter.Write(b)
+	d.written += int64(n)
+	return n, err
+}
+
+func (d *responseWriterDelegator) Status() int {
+	return d.status
+}
+
+func (d *responseWriterDelegator) Written() int64 {
+	return d.written
+}
+
+type closeNotifierDelegator struct{ *responseWriterDelegator }
+type flusherDelegator struct{ *responseWriterDelegator }
+type hijackerDelegator struct{ *responseWriterDelegator }
+type readerFromDelegator struct{ *responseWriterDelegator }
+type pusherDelegator struct{ *responseWriterDelegator }
+
+func (d closeNotifierDelegator) CloseNotify() <-chan bool {
+	return d.ResponseWriter.(http.CloseNotifier).CloseNotify()
+}
+
+func (d flusherDelegator) Flush() {
+	d.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (d hijackerDelegator) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return d.ResponseWriter.(http.Hijacker).Hijack()
+}
+
+func (d readerFromDelegator) ReadFrom(re io.Reader) (int64, error) {
+	n, err := d.ResponseWriter.(io.ReaderFrom).ReadFrom(re)
+	d.written += n
+	return n, err
+}
+
+func (d pusherDelegator) Push(target string, opts *http.PushOptions) error {
+	return d.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+const (
+	closeNotifierFlag = 1 << iota
+	flusherFlag
+	hijackerFlag
+	readerFromFlag
+	pusherFlag
+)
+
+// pickDelegator maps the bitmask of optional interfaces a ResponseWriter
+// supports to a delegator value that forwards exactly that set, so that
+// type assertions against the wrapped writer behave the same as they would
+// against the original.
+var pickDelegator = make([]func(*responseWriterDelegator) delegator, 32)
+
+func init() {
+	pickDelegator[0] = func(d *responseWriterDelegator) delegator { return d }
+	pickDelegator[closeNotifierFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+		}{d, closeNotifierDelegator{d}}
+	}
+	pickDelegator[flusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+		}{d, flusherDelegator{d}}
+	}
+	pickDelegator[hijackerFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Hijacker
+		}{d, hijackerDelegator{d}}
+	}
+	pickDelegator[readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			io.ReaderFrom
+		}{d, readerFromDelegator{d}}
+	}
+	pickDelegator[pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Pusher
+		}{d, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|hijackerFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Hijacker
+		}{d, closeNotifierDelegator{d}, hijackerDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			io.ReaderFrom
+		}{d, closeNotifierDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[flusherFlag|hijackerFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			http.Hijacker
+		}{d, flusherDelegator{d}, hijackerDelegator{d}}
+	}
+	pickDelegator[flusherFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			io.ReaderFrom
+		}{d, flusherDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[flusherFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			http.Pusher
+		}{d, flusherDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[hijackerFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Hijacker
+			io.ReaderFrom
+		}{d, hijackerDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[hijackerFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Hijacker
+			http.Pusher
+		}{d, hijackerDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			io.ReaderFrom
+			http.Pusher
+		}{d, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|hijackerFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			http.Hijacker
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, hijackerDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			io.ReaderFrom
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|hijackerFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Hijacker
+			io.ReaderFrom
+		}{d, closeNotifierDelegator{d}, hijackerDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|hijackerFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Hijacker
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, hijackerDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			io.ReaderFrom
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[flusherFlag|hijackerFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{d, flusherDelegator{d}, hijackerDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[flusherFlag|hijackerFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{d, flusherDelegator{d}, hijackerDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[flusherFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{d, flusherDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[hijackerFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{d, hijackerDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|hijackerFlag|readerFromFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, hijackerDelegator{d}, readerFromDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|hijackerFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, hijackerDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|hijackerFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, hijackerDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[flusherFlag|hijackerFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{d, flusherDelegator{d}, hijackerDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+	pickDelegator[closeNotifierFlag|flusherFlag|hijackerFlag|readerFromFlag|pusherFlag] = func(d *responseWriterDelegator) delegator {
+		return struct {
+			*responseWriterDelegator
+			http.CloseNotifier
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{d, closeNotifierDelegator{d}, flusherDelegator{d}, hijackerDelegator{d}, readerFromDelegator{d}, pusherDelegator{d}}
+	}
+}
+
+// newDelegator wraps w so that the status code and bytes written can be
+// observed after the handler returns, while preserving whichever of
+// CloseNotifier/Flusher/Hijacker/ReaderFrom/Pusher w itself implements.
+func newDelegator(w http.ResponseWriter, observeWriteHeader func(status int)) delegator {
+	d := &responseWriterDelegator{
+		ResponseWriter:     w,
+		observeWriteHeader: observeWriteHeader,
+	}
+
+	id := 0
+	if _, ok := w.(http.CloseNotifier); ok {
+		id |= closeNotifierFlag
+	}
+	if _, ok := w.(http.Flusher); ok {
+		id |= flusherFlag
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		id |= hijackerFlag
+	}
+	if _, ok := w.(io.ReaderFrom); ok {
+		id |= readerFromFlag
+	}
+	if _, ok := w.(http.Pusher); ok {
+		id |= pusherFlag
+	}
+
+	return pickDelegator[id](d)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// hijackableRecorder adds a no-op Hijacker implementation on top of
+// httptest.ResponseRecorder so we can exercise the hijacker delegator path.
+type hijackableRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (h *hijackableRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h.hijacked = true
+	server, _ := net.Pipe()
+	return server, bufio.NewReadWriter(bufio.NewReader(server), bufio.NewWriter(server)), nil
+}
+
+func TestMetricsMiddlewareStatusCodes(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{"ok", http.StatusOK, "fine"},
+		{"not found", http.StatusNotFound, "missing"},
+		{"bad request", http.StatusBadRequest, "nope"},
+		{"server error", http.StatusInternalServerError, "boom"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := metricsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				w.Write([]byte(tc.body))
+			}, "/test")
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			if rec.Code != tc.status {
+				t.Fatalf("expected status %d, got %d", tc.status, rec.Code)
+			}
+		})
+	}
+}
+
+func TestMetricsMiddlewareImplicitOK(t *testing.T) {
+	handler := metricsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("no explicit WriteHeader"))
+	}, "/implicit")
+
+	req := httptest.NewRequest(http.MethodGet, "/implicit", nil)
+	rec := httptest.NewRecorder()
+
+	handler(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected implicit status 200, got %d", rec.Code)
+	}
+}
+
+func TestMetricsMiddlewareHijack(t *testing.T) {
+	rec := &hijackableRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	handler := metricsMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		hj, ok := w.(http.Hijacker)
+		if !ok {
+			t.Fatal("expected wrapped ResponseWriter to implement http.Hijacker")
+		}
+		conn, _, err := hj.Hijack()
+		if err != nil {
+			t.Fatalf("hijack failed: %v", err)
+		}
+		conn.Close()
+	}, "/hijack")
+
+	req := httptest.NewRequest(http.MethodGet, "/hijack", nil)
+	handler(rec, req)
+
+	if !rec.hijacked {
+		t.Fatal("expected underlying ResponseWriter to have been hijacked")
+	}
+}
+
+func TestNewDelegatorHidesUnsupportedInterfaces(t *testing.T) {
+	rec := httptest.NewRecorder()
+	d := newDelegator(rec, nil)
+
+	if _, ok := d.(http.Hijacker); ok {
+		t.Fatal("expected delegator not to implement http.Hijacker when underlying writer doesn't")
+	}
+}
+
+func TestNewDelegatorTracksWrittenBytes(t *testing.T) {
+	rec := httptest.NewRecorder()
+	d := newDelegator(rec, nil)
+
+	d.Write([]byte("hello"))
+	d.Write([]byte(" world"))
+
+	if got, want := d.Written(), int64(len("hello world")); got != want {
+		t.Fatalf("expected %d bytes written, got %d", want, got)
+	}
+	if got := d.Status(); got != http.StatusOK {
+		t.Fatalf("expected implicit status 200, got %d", got)
+	}
+}
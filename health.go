@@ -0,0 +1,58 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+)
+
+// HealthRegistry tracks readiness checks contributed by other subsystems
+// (e.g. the mock config loader, the outbound HTTP client pool). The
+// /ready endpoint reports healthy only once every registered check passes.
+type HealthRegistry struct {
+	mu     sync.Mutex
+	checks map[string]func() bool
+}
+
+// NewHealthRegistry returns an empty registry; subsystems call Register as
+// they start up.
+func NewHealthRegistry() *HealthRegistry {
+	return &HealthRegistry{checks: make(map[string]func() bool)}
+}
+
+// Register adds a named readiness check. Registering the same name again
+// replaces the previous check.
+func (h *HealthRegistry) Register(name string, check func() bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.checks[name] = check
+}
+
+// Ready reports whether every registered check currently passes.
+func (h *HealthRegistry) Ready() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, check := range h.checks {
+		if !check() {
+			return false
+		}
+	}
+	return true
+}
+
+// LiveHandler always reports 200 once the process can serve HTTP at all;
+// it's deliberately independent of HealthRegistry so a stuck dependency
+// can't take the pod out of the load balancer's liveness probe.
+func (h *HealthRegistry) LiveHandler(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// ReadyHandler reports 200 once every registered check passes, 503 otherwise.
+func (h *HealthRegistry) ReadyHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.Ready() {
+		http.Error(w, "not ready", http.StatusServiceUnavailable)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthRegistryReadyWithNoChecks(t *testing.T) {
+	h := NewHealthRegistry()
+	if !h.Ready() {
+		t.Fatal("expected registry with no checks to be ready")
+	}
+}
+
+func TestHealthRegistryNotReadyUntilChecksPass(t *testing.T) {
+	h := NewHealthRegistry()
+	ready := false
+	h.Register("dependency", func() bool { return ready })
+
+	rec := httptest.NewRecorder()
+	h.ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 before dependency is ready, got %d", rec.Code)
+	}
+
+	ready = true
+	rec = httptest.NewRecorder()
+	h.ReadyHandler(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 once dependency is ready, got %d", rec.Code)
+	}
+}
+
+func TestLiveHandlerIgnoresReadiness(t *testing.T) {
+	h := NewHealthRegistry()
+	h.Register("dependency", func() bool { return false })
+
+	rec := httptest.NewRecorder()
+	h.LiveHandler(rec, httptest.NewRequest(http.MethodGet, "/live", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected /live to report 200 regardless of readiness checks, got %d", rec.Code)
+	}
+}
+
+func TestChainOrdersOutermostFirst(t *testing.T) {
+	var order []string
+	mw := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	handler := Chain(mw("first"), mw("second"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"first", "second", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("expected order %v, got %v", want, order)
+	}
+	for i, name := range want {
+		if order[i] != name {
+			t.Fatalf("expected order %v, got %v", want, order)
+		}
+	}
+}
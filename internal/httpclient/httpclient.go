@@ -0,0 +1,80 @@
+// Package httpclient provides an *http.Client whose RoundTripper is
+// instrumented with the same kind of metrics the server side already
+// exposes, so outbound calls show up in Prometheus next to inbound ones.
+package httpclient
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	requestsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_client_requests_total",
+			Help: "Total number of outbound HTTP requests made via this client",
+		},
+		[]string{"method", "host", "status"},
+	)
+
+	requestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name: "http_client_request_duration_seconds",
+			Help: "Outbound HTTP request latency",
+		},
+		[]string{"method", "host"},
+	)
+
+	inFlight = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "http_client_in_flight_requests",
+			Help: "Number of outbound HTTP requests currently in flight",
+		},
+		[]string{"host"},
+	)
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, requestDuration, inFlight)
+}
+
+// instrumentedRoundTripper wraps another http.RoundTripper, recording
+// request count per method/host/status, duration per method/host and
+// in-flight requests per host.
+type instrumentedRoundTripper struct {
+	next http.RoundTripper
+}
+
+func (rt instrumentedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	inFlight.WithLabelValues(host).Inc()
+	defer inFlight.WithLabelValues(host).Dec()
+
+	start := time.Now()
+	resp, err := rt.next.RoundTrip(req)
+	duration := time.Since(start).Seconds()
+
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+
+	requestsTotal.WithLabelValues(req.Method, host, status).Inc()
+	requestDuration.WithLabelValues(req.Method, host).Observe(duration)
+
+	return resp, err
+}
+
+// New returns a *http.Client that instruments every request it makes.
+// A nil base uses http.DefaultTransport.
+func New(base http.RoundTripper) *http.Client {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport: instrumentedRoundTripper{next: base},
+	}
+}
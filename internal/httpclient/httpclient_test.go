@@ -0,0 +1,65 @@
+package httpclient
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	io_prometheus_client "github.com/prometheus/client_model/go"
+)
+
+func counterValue(t *testing.T, method, host, status string) float64 {
+	t.Helper()
+	var m io_prometheus_client.Metric
+	if err := requestsTotal.WithLabelValues(method, host, status).Write(&m); err != nil {
+		t.Fatalf("reading counter: %v", err)
+	}
+	return m.GetCounter().GetValue()
+}
+
+func TestNewInstrumentsRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer server.Close()
+
+	client := New(nil)
+
+	before := counterValue(t, http.MethodGet, server.Listener.Addr().String(), "418")
+
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	after := counterValue(t, http.MethodGet, server.Listener.Addr().String(), "418")
+	if after != before+1 {
+		t.Fatalf("expected counter to increment by 1, went from %v to %v", before, after)
+	}
+}
+
+func TestNewUsesProvidedBaseTransport(t *testing.T) {
+	called := false
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		called = true
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Header: make(http.Header)}, nil
+	})
+
+	client := New(base)
+	req, _ := http.NewRequest(http.MethodGet, "http://example.invalid", nil)
+	if _, err := client.Do(req); err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+
+	if !called {
+		t.Fatal("expected the provided base transport to be used")
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+var _ prometheus.Collector = requestsTotal
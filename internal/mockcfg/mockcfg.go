@@ -0,0 +1,81 @@
+// Package mockcfg loads the YAML configuration that drives the playground's
+// mock-endpoint mode, letting it stand in as an HTTP fixture without a
+// recompile.
+package mockcfg
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry describes a single canned response for a path.
+type Entry struct {
+	Method  string            `yaml:"method"`
+	Status  int               `yaml:"status"`
+	Content string            `yaml:"content"`
+	Body    string            `yaml:"body"`
+	Headers map[string]string `yaml:"headers"`
+	DelayMs int               `yaml:"delayMs"`
+}
+
+// Config is the parsed form of the mock config file: a path mapped to the
+// list of method-specific entries registered for it.
+type Config struct {
+	Endpoints map[string][]Entry `yaml:"endpoints"`
+}
+
+// Load reads and validates the mock config at path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("mockcfg: reading %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("mockcfg: parsing %s: %w", path, err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("mockcfg: %s: %w", path, err)
+	}
+
+	return &cfg, nil
+}
+
+func (c *Config) validate() error {
+	for path, entries := range c.Endpoints {
+		if len(entries) == 0 {
+			return fmt.Errorf("endpoint %q has no entries", path)
+		}
+		seen := make(map[string]bool, len(entries))
+		for i, e := range entries {
+			if e.Method == "" {
+				return fmt.Errorf("endpoint %q entry %d: method is required", path, i)
+			}
+			if seen[e.Method] {
+				return fmt.Errorf("endpoint %q: method %q declared more than once", path, e.Method)
+			}
+			seen[e.Method] = true
+			if e.Status < 100 || e.Status > 599 {
+				return fmt.Errorf("endpoint %q entry %d: status %d out of range", path, i, e.Status)
+			}
+			if e.DelayMs < 0 {
+				return fmt.Errorf("endpoint %q entry %d: delayMs must not be negative", path, i)
+			}
+		}
+	}
+	return nil
+}
+
+// Lookup finds the entry registered for method on path, if any.
+func (c *Config) Lookup(path, method string) (Entry, bool) {
+	for _, e := range c.Endpoints[path] {
+		if e.Method == method {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
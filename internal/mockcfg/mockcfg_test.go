@@ -0,0 +1,101 @@
+package mockcfg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mock.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("writing fixture config: %v", err)
+	}
+	return path
+}
+
+func TestLoadMultiMethodPath(t *testing.T) {
+	path := writeConfig(t, `
+endpoints:
+  /api/widgets:
+    - method: GET
+      status: 200
+      content: application/json
+      body: '{"widgets":[]}'
+    - method: POST
+      status: 201
+      content: application/json
+      body: '{"id":1}'
+      headers:
+        X-Created: "true"
+      delayMs: 10
+`)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	get, ok := cfg.Lookup("/api/widgets", "GET")
+	if !ok || get.Status != 200 {
+		t.Fatalf("expected GET entry with status 200, got %+v (ok=%v)", get, ok)
+	}
+
+	post, ok := cfg.Lookup("/api/widgets", "POST")
+	if !ok || post.Status != 201 || post.DelayMs != 10 || post.Headers["X-Created"] != "true" {
+		t.Fatalf("expected POST entry with status 201, delay 10, header set, got %+v (ok=%v)", post, ok)
+	}
+
+	if _, ok := cfg.Lookup("/api/widgets", "DELETE"); ok {
+		t.Fatal("expected no entry for DELETE")
+	}
+}
+
+func TestLoadMalformedYAML(t *testing.T) {
+	path := writeConfig(t, "endpoints: [this is not a map")
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error loading malformed YAML")
+	}
+}
+
+func TestLoadRejectsDuplicateMethod(t *testing.T) {
+	path := writeConfig(t, `
+endpoints:
+  /api/widgets:
+    - method: GET
+      status: 200
+      content: text/plain
+      body: "first"
+    - method: GET
+      status: 200
+      content: text/plain
+      body: "second"
+`)
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for duplicate method on the same path")
+	}
+}
+
+func TestLoadRejectsInvalidStatus(t *testing.T) {
+	path := writeConfig(t, `
+endpoints:
+  /api/widgets:
+    - method: GET
+      status: 999
+      content: text/plain
+      body: "oops"
+`)
+
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected error for out-of-range status")
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("expected error for missing config file")
+	}
+}
@@ -2,13 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/laszlocph-amp/orka-playground/internal/httpclient"
+	"github.com/laszlocph-amp/orka-playground/internal/mockcfg"
 )
 
 var (
@@ -20,10 +26,24 @@ var (
 		[]string{"method", "endpoint", "status"},
 	)
 
-	httpRequestDuration = prometheus.NewHistogramVec(
+	// httpRequestDuration defaults to defaultLatencyBuckets; main replaces it
+	// with a custom-bucketed histogram when -latency-buckets is set.
+	httpRequestDuration = newRequestDurationHistogram(defaultLatencyBuckets)
+
+	httpResponseSize = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
-			Name: "http_request_duration_seconds",
-			Help: "HTTP request latency",
+			Name:    "http_response_size_bytes",
+			Help:    "HTTP response size in bytes",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	httpRequestSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_size_bytes",
+			Help:    "HTTP request size in bytes",
+			Buckets: prometheus.ExponentialBuckets(64, 4, 8),
 		},
 		[]string{"method", "endpoint"},
 	)
@@ -31,27 +51,59 @@ var (
 
 func init() {
 	prometheus.MustRegister(httpRequestsTotal)
-	prometheus.MustRegister(httpRequestDuration)
+	prometheus.MustRegister(httpResponseSize)
+	prometheus.MustRegister(httpRequestSize)
+}
+
+// newRequestDurationHistogram builds and registers httpRequestDuration with
+// the given bucket bounds. Each call registers a new collector, so the
+// previous one has to be unregistered first, as main does for
+// -latency-buckets before any request is served.
+func newRequestDurationHistogram(buckets []float64) *prometheus.HistogramVec {
+	h := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency",
+			Buckets: buckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+	prometheus.MustRegister(h)
+	return h
 }
 
+// metricsMiddleware wraps next's ResponseWriter in a delegator so it can
+// observe the status code and response size the handler actually produced,
+// instead of assuming a 200.
 func metricsMiddleware(next http.HandlerFunc, endpoint string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		
-		next.ServeHTTP(w, r)
-		
+
+		d := newDelegator(w, nil)
+		next.ServeHTTP(d, r)
+
 		duration := time.Since(start).Seconds()
-		httpRequestsTotal.WithLabelValues(r.Method, endpoint, "200").Inc()
+		status := strconv.Itoa(d.Status())
+		if status == "0" {
+			status = "200"
+		}
+		httpRequestsTotal.WithLabelValues(r.Method, endpoint, status).Inc()
 		httpRequestDuration.WithLabelValues(r.Method, endpoint).Observe(duration)
+		httpResponseSize.WithLabelValues(r.Method, endpoint).Observe(float64(d.Written()))
+
+		requestSize := r.ContentLength
+		if requestSize < 0 {
+			// Unknown length (e.g. chunked request bodies) reports -1; treat as 0
+			// rather than feeding a negative value into the histogram.
+			requestSize = 0
+		}
+		httpRequestSize.WithLabelValues(r.Method, endpoint).Observe(float64(requestSize))
 	}
 }
 
 
 
 
-
-
-
 type SampleRequest struct {
 	Name    string `json:"name"`
 	Message string `json:"message"`
@@ -86,22 +138,88 @@ func samplePostHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func main() {
+	configPath := flag.String("config", "", "path to a YAML file of mock endpoints to serve instead of recompiling")
+	debug := flag.Bool("debug", false, "expose debug-only endpoints such as /env")
+	upstream := flag.String("upstream", "", "upstream URL for /demo/upstream to call via the instrumented HTTP client")
+	latencyBuckets := flag.String("latency-buckets", "", "comma-separated, strictly increasing histogram buckets (seconds) for http_request_duration_seconds; defaults to a set tuned for API SLOs")
+	flag.Parse()
+
+	if *latencyBuckets != "" {
+		buckets, err := parseBuckets(*latencyBuckets)
+		if err != nil {
+			log.Fatalf("invalid -latency-buckets: %v", err)
+		}
+		prometheus.Unregister(httpRequestDuration)
+		httpRequestDuration = newRequestDurationHistogram(buckets)
+	}
+
+	prometheus.MustRegister(collectors.NewBuildInfoCollector())
+	prometheus.MustRegister(collectors.NewGoCollector(
+		collectors.WithGoCollections(collectors.GoRuntimeMetricsCollection),
+	))
+
 	// Metrics server on dedicated port
 	metricsServeMux := http.NewServeMux()
-	metricsServeMux.Handle("/metrics", promhttp.Handler())
-	
+	metricsServeMux.Handle("/metrics", promhttp.HandlerFor(prometheus.DefaultGatherer, promhttp.HandlerOpts{
+		ErrorLog:      log.Default(),
+		ErrorHandling: promhttp.ContinueOnError,
+		Registry:      prometheus.DefaultRegisterer,
+	}))
+
 	go func() {
 		log.Println("Metrics server starting on :9090")
 		log.Fatal(http.ListenAndServe(":9090", metricsServeMux))
 	}()
 
+	health := NewHealthRegistry()
+
 	// Main server
 	mainServeMux := http.NewServeMux()
-	mainServeMux.HandleFunc("/api/sample2", metricsMiddleware(samplePostHandler, "/api/sample2"))
-	
+
+	base := func(endpoint string) Middleware {
+		return Chain(requestIDMiddleware, loggingMiddleware, metricsMiddlewareFor(endpoint))
+	}
+
+	mainServeMux.Handle("/api/sample2", base("/api/sample2")(http.HandlerFunc(samplePostHandler)))
+	mainServeMux.Handle("/bad", base("/bad")(http.HandlerFunc(badHandler)))
+	mainServeMux.HandleFunc("/live", health.LiveHandler)
+	mainServeMux.HandleFunc("/ready", health.ReadyHandler)
+
+	if *debug {
+		mainServeMux.HandleFunc("/env", envHandler)
+	}
+
+	if *upstream != "" {
+		client := httpclient.New(nil)
+		mainServeMux.Handle("/demo/upstream", base("/demo/upstream")(upstreamDemoHandler(client, *upstream)))
+
+		checker := newUpstreamChecker(client, *upstream)
+		go checker.run(10 * time.Second)
+		health.Register("upstream", checker.ready)
+	}
+
+	if *configPath != "" {
+		cfg, err := mockcfg.Load(*configPath)
+		if err != nil {
+			log.Fatalf("loading mock config: %v", err)
+		}
+		registerMockEndpoints(mainServeMux, cfg)
+		health.Register("mockcfg", func() bool { return true })
+		log.Printf("Registered %d mock endpoint(s) from %s", len(cfg.Endpoints), *configPath)
+	}
+
 	log.Println("Main server starting on :8080")
 	log.Println("Endpoints available:")
 	log.Println("  POST /api/sample2 - Sample endpoint")
+	log.Println("  GET  /live        - Liveness probe")
+	log.Println("  GET  /ready       - Readiness probe")
+	log.Println("  GET  /bad         - Random 4xx/5xx for alert testing")
+	if *debug {
+		log.Println("  GET  /env         - Process environment (debug mode)")
+	}
+	if *upstream != "" {
+		log.Printf("  GET  /demo/upstream - Calls %s via the instrumented HTTP client", *upstream)
+	}
 	log.Println("Metrics server available on :9090/metrics")
 	log.Fatal(http.ListenAndServe(":8080", mainServeMux))
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+func TestBuildInfoAndGoCollectorsRegister(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewBuildInfoCollector())
+	reg.MustRegister(collectors.NewGoCollector(
+		collectors.WithGoCollections(collectors.GoRuntimeMetricsCollection),
+	))
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+
+	var sawBuildInfo bool
+	for _, f := range families {
+		if f.GetName() == "go_build_info" {
+			sawBuildInfo = true
+		}
+	}
+	if !sawBuildInfo {
+		t.Fatal("expected go_build_info to be registered")
+	}
+}
+
+func TestHistogramWithDefaultLatencyBucketsHasOneBucketPerBound(t *testing.T) {
+	h := prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "test_request_duration_seconds",
+			Help:    "test only",
+			Buckets: defaultLatencyBuckets,
+		},
+		[]string{"method", "endpoint"},
+	)
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(h)
+	h.WithLabelValues("GET", "/x").Observe(0.02)
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("gathering metrics: %v", err)
+	}
+	if len(families) != 1 || len(families[0].Metric[0].Histogram.Bucket) != len(defaultLatencyBuckets) {
+		t.Fatalf("expected histogram with %d buckets", len(defaultLatencyBuckets))
+	}
+}
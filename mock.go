@@ -0,0 +1,41 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/laszlocph-amp/orka-playground/internal/mockcfg"
+)
+
+// registerMockEndpoints registers one metrics-instrumented handler per path
+// declared in cfg, dispatching to the entry matching the request method.
+func registerMockEndpoints(mux *http.ServeMux, cfg *mockcfg.Config) {
+	for path := range cfg.Endpoints {
+		path := path
+		chain := Chain(requestIDMiddleware, loggingMiddleware, metricsMiddlewareFor(path))
+		mux.Handle(path, chain(http.HandlerFunc(mockHandler(cfg, path))))
+	}
+}
+
+func mockHandler(cfg *mockcfg.Config, path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		entry, ok := cfg.Lookup(path, r.Method)
+		if !ok {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if entry.DelayMs > 0 {
+			time.Sleep(time.Duration(entry.DelayMs) * time.Millisecond)
+		}
+
+		for k, v := range entry.Headers {
+			w.Header().Set(k, v)
+		}
+		if entry.Content != "" {
+			w.Header().Set("Content-Type", entry.Content)
+		}
+		w.WriteHeader(entry.Status)
+		w.Write([]byte(entry.Body))
+	}
+}
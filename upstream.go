@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// upstreamDemoHandler calls upstream via client and relays its response,
+// so both http_client_* and http_* metrics can be observed end-to-end from
+// a single request.
+func upstreamDemoHandler(client *http.Client, upstream string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp, err := client.Get(upstream)
+		if err != nil {
+			http.Error(w, "upstream request failed: "+err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		for k, v := range resp.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}
+}
+
+// upstreamChecker polls an upstream URL on an interval and reports whether
+// the last poll succeeded, so /ready can reflect real upstream reachability
+// instead of always reporting healthy.
+type upstreamChecker struct {
+	client  *http.Client
+	url     string
+	healthy atomic.Bool
+}
+
+func newUpstreamChecker(client *http.Client, url string) *upstreamChecker {
+	return &upstreamChecker{client: client, url: url}
+}
+
+func (c *upstreamChecker) check() {
+	resp, err := c.client.Get(c.url)
+	if err != nil {
+		c.healthy.Store(false)
+		return
+	}
+	resp.Body.Close()
+	c.healthy.Store(resp.StatusCode < http.StatusInternalServerError)
+}
+
+// run polls the upstream every interval until the process exits.
+func (c *upstreamChecker) run(interval time.Duration) {
+	for {
+		c.check()
+		time.Sleep(interval)
+	}
+}
+
+func (c *upstreamChecker) ready() bool {
+	return c.healthy.Load()
+}
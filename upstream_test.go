@@ -0,0 +1,37 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUpstreamCheckerReflectsReachability(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	checker := newUpstreamChecker(server.Client(), server.URL)
+
+	checker.check()
+	if !checker.ready() {
+		t.Fatal("expected checker to report ready when upstream returns 200")
+	}
+
+	status = http.StatusServiceUnavailable
+	checker.check()
+	if checker.ready() {
+		t.Fatal("expected checker to report not ready when upstream returns 503")
+	}
+}
+
+func TestUpstreamCheckerNotReadyOnConnectionError(t *testing.T) {
+	checker := newUpstreamChecker(http.DefaultClient, "http://127.0.0.1:0")
+
+	checker.check()
+	if checker.ready() {
+		t.Fatal("expected checker to report not ready when upstream is unreachable")
+	}
+}